@@ -1,68 +1,83 @@
 package bimap
 
 import (
-	"sort"
+	"cmp"
+	"container/list"
+	"iter"
 	"sync"
+	"time"
 )
 
-type ItemWithExtra struct {
-	Key   string
-	Value string
-	Extra any
+// ItemWithExtra is a single key/value/extra triple returned by GetWithRange.
+type ItemWithExtra[K cmp.Ordered, V comparable, E any] struct {
+	Key   K
+	Value V
+	Extra E
 }
 
-type entry struct {
-	Value string
-	Extra any // optional: int, string, map, set, struct, etc.
+type entry[V comparable, E any] struct {
+	Value    V
+	Extra    E         // optional: int, string, map, set, struct, etc.
+	expireAt time.Time // zero means no TTL
 }
 
-// BiMap is a thread-safe bidirectional map structure
-type BiMap struct {
+// BiMap is a thread-safe bidirectional map structure. Keys must be ordered
+// (cmp.Ordered) since BiMap keeps them sorted for deterministic, paginated
+// iteration via GetWithRange; values only need to be comparable so they can
+// be used as keys on the reverse map. Extra is an arbitrary per-entry payload
+// that callers no longer have to type-assert back out of an `any`.
+type BiMap[K cmp.Ordered, V comparable, E any] struct {
 	mu         sync.RWMutex
-    keyToEntry map[string]entry
-	valueToKey map[string]string
-	keys       []string // Ordered keys for deterministic iteration
+	keyToEntry map[K]entry[V, E]
+	valueToKey map[V]K
+	index      *orderedIndex[K] // Ordered keys for deterministic, rank-based iteration
+
+	maxSize     int           // 0 means unbounded
+	defaultTTL  time.Duration // 0 means no expiry unless SetWithTTL is used
+	onEvict     func(key K, value V, extra E, reason EvictReason)
+	expiries    *expiryHeap[K] // entries with a TTL, ordered by soonest expiry
+	insertOrder *list.List     // insertion order, used for CapacityExceeded eviction when no entry has a TTL
+	insertElems map[K]*list.Element
+
+	janitorStop chan struct{}
+	janitorDone chan struct{}
 }
 
 // NewBiMap creates a new BiMap
-func NewBiMap() *BiMap {
-	return &BiMap{
-		keyToEntry: make(map[string]entry),
-		valueToKey: make(map[string]string),
-		keys:       make([]string, 0),
+func NewBiMap[K cmp.Ordered, V comparable, E any]() *BiMap[K, V, E] {
+	return &BiMap[K, V, E]{
+		keyToEntry:  make(map[K]entry[V, E]),
+		valueToKey:  make(map[V]K),
+		index:       newOrderedIndex[K](),
+		expiries:    newExpiryHeap[K](),
+		insertOrder: list.New(),
+		insertElems: make(map[K]*list.Element),
 	}
 }
 
+// StringBiMap is the string/string/any instantiation used by existing call
+// sites that don't need typed extras or non-string keys.
+type StringBiMap = BiMap[string, string, any]
+
+// NewStringBiMap creates a new StringBiMap.
+func NewStringBiMap() *StringBiMap {
+	return NewBiMap[string, string, any]()
+}
+
 // Set adds a key-value pair to the BiMap (thread-safe)
-func (bm *BiMap) Set(key string, value string) {
-	bm.SetWithExtra(key, value, nil)
+func (bm *BiMap[K, V, E]) Set(key K, value V) {
+	var extra E
+	bm.SetWithExtra(key, value, extra)
 }
 
-func (bm *BiMap) SetWithExtra(key, value string, extra any) {
+func (bm *BiMap[K, V, E]) SetWithExtra(key K, value V, extra E) {
 	bm.mu.Lock()
 	defer bm.mu.Unlock()
-
-	if oldentry, exists := bm.keyToEntry[key]; exists {
-		delete(bm.valueToKey, oldentry.Value)
-	} else {
-		bm.keys = append(bm.keys, key)
-		sort.Strings(bm.keys)
-	}
-
-	if oldKey, exists := bm.valueToKey[value]; exists {
-		delete(bm.keyToEntry, oldKey)
-		bm.removeKeyFromList(oldKey)
-	}
-
-	bm.keyToEntry[key] = entry{
-		Value: value,
-		Extra: extra, // can be nil or any type
-	}
-	bm.valueToKey[value] = key
+	bm.setLocked(key, value, extra, bm.defaultTTL)
 }
 
 // Get returns the value for a given key (thread-safe)
-func (bm *BiMap) Get(key string) (string, bool) {
+func (bm *BiMap[K, V, E]) Get(key K) (V, bool) {
 	bm.mu.RLock()
 	defer bm.mu.RUnlock()
 
@@ -70,46 +85,46 @@ func (bm *BiMap) Get(key string) (string, bool) {
 	return entry.Value, exists
 }
 
-func (bm *BiMap) GetExtra(key string) (string, any, bool) {
+func (bm *BiMap[K, V, E]) GetExtra(key K) (V, E, bool) {
 	bm.mu.RLock()
 	defer bm.mu.RUnlock()
 
 	entry, exists := bm.keyToEntry[key]
 	if !exists {
-		return "", nil, false
+		var zero V
+		var zeroExtra E
+		return zero, zeroExtra, false
 	}
 	return entry.Value, entry.Extra, true
 }
 
 // GetByValue returns the key for a given value (thread-safe)
-func (bm *BiMap) GetByValue(value string) (string, bool) {
+func (bm *BiMap[K, V, E]) GetByValue(value V) (K, bool) {
 	bm.mu.RLock()
 	defer bm.mu.RUnlock()
 	key, exists := bm.valueToKey[value]
 	return key, exists
 }
 
-// GetValuesWithRange returns key-value pairs from the BiMap with pagination
+// GetWithRange returns key-value pairs from the BiMap with pagination.
 // Uses ordered keys for deterministic results
-func (bm *BiMap) GetWithRange(start, limit int) []ItemWithExtra {
+func (bm *BiMap[K, V, E]) GetWithRange(start, limit int) []ItemWithExtra[K, V, E] {
 	bm.mu.RLock()
 	defer bm.mu.RUnlock()
 
-	if start >= len(bm.keys) || limit <= 0 {
+	keys := bm.index.GetRange(start, limit)
+	if len(keys) == 0 {
 		return nil
 	}
 
-	end := min(start+limit, len(bm.keys))
-	result := make([]ItemWithExtra, 0, end-start)
-
-	for i := start; i < end; i++ {
-		key := bm.keys[i]
+	result := make([]ItemWithExtra[K, V, E], 0, len(keys))
+	for _, key := range keys {
 		value := bm.keyToEntry[key]
 
-		result = append(result, ItemWithExtra{
+		result = append(result, ItemWithExtra[K, V, E]{
 			Key:   key,
 			Value: value.Value,
-			Extra: value.Extra, // can be nil
+			Extra: value.Extra, // can be zero value
 		})
 	}
 
@@ -117,31 +132,100 @@ func (bm *BiMap) GetWithRange(start, limit int) []ItemWithExtra {
 }
 
 // Delete removes a key-value pair from the BiMap (thread-safe)
-func (bm *BiMap) Delete(key string) {
+func (bm *BiMap[K, V, E]) Delete(key K) {
 	bm.mu.Lock()
 	defer bm.mu.Unlock()
 
-	if entry, exists := bm.keyToEntry[key]; exists {
-		delete(bm.keyToEntry, key)
-		delete(bm.valueToKey, entry.Value)
-		bm.removeKeyFromList(key)
-	}
+	bm.sweepExpiredLocked()
+	bm.removeLocked(key, Deleted)
 }
 
 // Len returns the number of mappings (thread-safe)
-func (bm *BiMap) Len() int {
+func (bm *BiMap[K, V, E]) Len() int {
 	bm.mu.RLock()
 	defer bm.mu.RUnlock()
 	return len(bm.keyToEntry)
 }
 
-// removeKeyFromList removes a key from the ordered keys list
-// Must be called with lock held
-func (bm *BiMap) removeKeyFromList(key string) {
-	for i, k := range bm.keys {
-		if k == key {
-			bm.keys = append(bm.keys[:i], bm.keys[i+1:]...)
-			break
-		}
+// Clear removes all mappings (thread-safe). It does not invoke OnEvict for
+// the discarded entries; that callback reports individual evictions, not
+// bulk resets.
+func (bm *BiMap[K, V, E]) Clear() {
+	bm.mu.Lock()
+	defer bm.mu.Unlock()
+
+	bm.keyToEntry = make(map[K]entry[V, E])
+	bm.valueToKey = make(map[V]K)
+	bm.index = newOrderedIndex[K]()
+	bm.expiries = newExpiryHeap[K]()
+	bm.insertOrder = list.New()
+	bm.insertElems = make(map[K]*list.Element)
+}
+
+// Contains reports whether key has a mapping (thread-safe)
+func (bm *BiMap[K, V, E]) Contains(key K) bool {
+	bm.mu.RLock()
+	defer bm.mu.RUnlock()
+	_, exists := bm.keyToEntry[key]
+	return exists
+}
+
+// ContainsValue reports whether value has a mapping (thread-safe)
+func (bm *BiMap[K, V, E]) ContainsValue(value V) bool {
+	bm.mu.RLock()
+	defer bm.mu.RUnlock()
+	_, exists := bm.valueToKey[value]
+	return exists
+}
+
+// Range calls f for every key/value/extra triple in sorted key order,
+// stopping early if f returns false. f is called while holding the read
+// lock, so it must not call back into bm.
+func (bm *BiMap[K, V, E]) Range(f func(key K, value V, extra E) bool) {
+	bm.mu.RLock()
+	defer bm.mu.RUnlock()
+
+	bm.index.Range(func(key K) bool {
+		e := bm.keyToEntry[key]
+		return f(key, e.Value, e.Extra)
+	})
+}
+
+// All returns an iterator over every key/value/extra triple in sorted key
+// order. The read lock is held for the duration of the yield, so the
+// iteration body must not call back into bm.
+func (bm *BiMap[K, V, E]) All() iter.Seq2[K, ItemWithExtra[K, V, E]] {
+	return func(yield func(K, ItemWithExtra[K, V, E]) bool) {
+		bm.mu.RLock()
+		defer bm.mu.RUnlock()
+
+		bm.index.Range(func(key K) bool {
+			e := bm.keyToEntry[key]
+			return yield(key, ItemWithExtra[K, V, E]{Key: key, Value: e.Value, Extra: e.Extra})
+		})
 	}
-}
\ No newline at end of file
+}
+
+// Keys returns an iterator over keys in sorted order. The read lock is held
+// for the duration of the yield.
+func (bm *BiMap[K, V, E]) Keys() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		bm.mu.RLock()
+		defer bm.mu.RUnlock()
+
+		bm.index.Range(yield)
+	}
+}
+
+// Values returns an iterator over values in sorted-key order. The read lock
+// is held for the duration of the yield.
+func (bm *BiMap[K, V, E]) Values() iter.Seq[V] {
+	return func(yield func(V) bool) {
+		bm.mu.RLock()
+		defer bm.mu.RUnlock()
+
+		bm.index.Range(func(key K) bool {
+			return yield(bm.keyToEntry[key].Value)
+		})
+	}
+}
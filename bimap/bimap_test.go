@@ -0,0 +1,179 @@
+package bimap
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBiMapSetGetByValue(t *testing.T) {
+	bm := NewBiMap[string, string, any]()
+
+	bm.Set("k1", "v1")
+	if v, ok := bm.Get("k1"); !ok || v != "v1" {
+		t.Fatalf("Get(k1) = (%v, %v), want (v1, true)", v, ok)
+	}
+	if k, ok := bm.GetByValue("v1"); !ok || k != "k1" {
+		t.Fatalf("GetByValue(v1) = (%v, %v), want (k1, true)", k, ok)
+	}
+	if _, ok := bm.Get("missing"); ok {
+		t.Fatalf("Get(missing) ok = true, want false")
+	}
+}
+
+// TestBiMapSetDisplacesExistingKeyAndValue checks that re-pointing a key to
+// a new value releases the key's old value, and that claiming a value
+// already owned by another key releases that key, keeping both maps in
+// sync with each other.
+func TestBiMapSetDisplacesExistingKeyAndValue(t *testing.T) {
+	bm := NewBiMap[string, string, any]()
+
+	bm.Set("k1", "v1")
+	bm.Set("k1", "v2") // same key, new value: v1 must be released
+
+	if _, ok := bm.GetByValue("v1"); ok {
+		t.Fatalf("GetByValue(v1) found after k1 was repointed to v2")
+	}
+	if v, ok := bm.Get("k1"); !ok || v != "v2" {
+		t.Fatalf("Get(k1) = (%v, %v), want (v2, true)", v, ok)
+	}
+	if bm.Len() != 1 {
+		t.Fatalf("Len() = %d, want 1", bm.Len())
+	}
+
+	bm.Set("k2", "v2") // same value, new key: k1 must be released
+
+	if _, ok := bm.Get("k1"); ok {
+		t.Fatalf("Get(k1) found after v2 was repointed to k2")
+	}
+	if k, ok := bm.GetByValue("v2"); !ok || k != "k2" {
+		t.Fatalf("GetByValue(v2) = (%v, %v), want (k2, true)", k, ok)
+	}
+	if bm.Len() != 1 {
+		t.Fatalf("Len() = %d, want 1", bm.Len())
+	}
+}
+
+// TestBiMapGetExtraRoundTrip checks that a typed, non-trivial Extra survives
+// a Set/Get round trip without any type assertion on the caller's part.
+func TestBiMapGetExtraRoundTrip(t *testing.T) {
+	type payload struct {
+		Count int
+		Tags  []string
+	}
+
+	bm := NewBiMap[string, string, payload]()
+	want := payload{Count: 3, Tags: []string{"a", "b"}}
+	bm.SetWithExtra("k1", "v1", want)
+
+	value, extra, ok := bm.GetExtra("k1")
+	if !ok || value != "v1" || !reflect.DeepEqual(extra, want) {
+		t.Fatalf("GetExtra(k1) = (%v, %+v, %v), want (v1, %+v, true)", value, extra, ok, want)
+	}
+
+	_, extra, ok = bm.GetExtra("missing")
+	if ok || !reflect.DeepEqual(extra, payload{}) {
+		t.Fatalf("GetExtra(missing) = (_, %+v, %v), want (_, zero value, false)", extra, ok)
+	}
+}
+
+// TestBiMapSetWithExtraDisplacesExtraToo checks that re-setting the same
+// key/value pair with a new extra replaces the old extra instead of keeping it.
+func TestBiMapSetWithExtraDisplacesExtraToo(t *testing.T) {
+	bm := NewBiMap[string, string, int]()
+	bm.SetWithExtra("k1", "v1", 1)
+	bm.SetWithExtra("k1", "v1", 2) // same key and value: only the extra changes
+
+	_, extra, ok := bm.GetExtra("k1")
+	if !ok || extra != 2 {
+		t.Fatalf("GetExtra(k1) = (_, %v, %v), want (_, 2, true)", extra, ok)
+	}
+	if bm.Len() != 1 {
+		t.Fatalf("Len() = %d, want 1", bm.Len())
+	}
+}
+
+func TestBiMapDeleteAndContains(t *testing.T) {
+	bm := NewBiMap[string, string, any]()
+	bm.Set("k1", "v1")
+
+	if !bm.Contains("k1") || !bm.ContainsValue("v1") {
+		t.Fatalf("Contains/ContainsValue false right after Set")
+	}
+
+	bm.Delete("k1")
+
+	if bm.Contains("k1") || bm.ContainsValue("v1") {
+		t.Fatalf("Contains/ContainsValue true after Delete")
+	}
+	if bm.Len() != 0 {
+		t.Fatalf("Len() = %d after Delete, want 0", bm.Len())
+	}
+
+	bm.Delete("never-existed") // must be a no-op, not a panic
+}
+
+func TestBiMapClearResetsEverything(t *testing.T) {
+	bm := NewBiMap[string, string, any]()
+	bm.Set("k1", "v1")
+	bm.Set("k2", "v2")
+
+	bm.Clear()
+
+	if bm.Len() != 0 {
+		t.Fatalf("Len() = %d after Clear, want 0", bm.Len())
+	}
+	if bm.Contains("k1") || bm.ContainsValue("v1") {
+		t.Fatalf("entries survived Clear")
+	}
+
+	// The BiMap must still be usable after Clear.
+	bm.Set("k3", "v3")
+	if v, ok := bm.Get("k3"); !ok || v != "v3" {
+		t.Fatalf("Get(k3) after Clear+Set = (%v, %v), want (v3, true)", v, ok)
+	}
+}
+
+// TestBiMapGetWithRangePagination checks that GetWithRange walks entries in
+// sorted key order and respects start/limit, including out-of-range and
+// zero-length edge cases.
+func TestBiMapGetWithRangePagination(t *testing.T) {
+	bm := NewBiMap[int, string, any]()
+	for i := 9; i >= 0; i-- { // insert out of order; GetWithRange must still be sorted
+		bm.SetWithExtra(i, "v"+string(rune('0'+i)), i*10)
+	}
+
+	page := bm.GetWithRange(2, 3)
+	want := []ItemWithExtra[int, string, any]{
+		{Key: 2, Value: "v2", Extra: 20},
+		{Key: 3, Value: "v3", Extra: 30},
+		{Key: 4, Value: "v4", Extra: 40},
+	}
+	if !reflect.DeepEqual(page, want) {
+		t.Fatalf("GetWithRange(2, 3) = %+v, want %+v", page, want)
+	}
+
+	// A limit larger than what remains must be truncated, not padded/erred.
+	tail := bm.GetWithRange(8, 10)
+	wantTail := []ItemWithExtra[int, string, any]{
+		{Key: 8, Value: "v8", Extra: 80},
+		{Key: 9, Value: "v9", Extra: 90},
+	}
+	if !reflect.DeepEqual(tail, wantTail) {
+		t.Fatalf("GetWithRange(8, 10) = %+v, want %+v", tail, wantTail)
+	}
+
+	if got := bm.GetWithRange(10, 5); got != nil {
+		t.Fatalf("GetWithRange(10, 5) (start == Len) = %+v, want nil", got)
+	}
+	if got := bm.GetWithRange(0, 0); got != nil {
+		t.Fatalf("GetWithRange(0, 0) = %+v, want nil", got)
+	}
+}
+
+func TestStringBiMapIsStringInstantiation(t *testing.T) {
+	bm := NewStringBiMap()
+	bm.Set("k1", "v1")
+	if v, ok := bm.Get("k1"); !ok || v != "v1" {
+		t.Fatalf("Get(k1) = (%v, %v), want (v1, true)", v, ok)
+	}
+}
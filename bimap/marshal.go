@@ -0,0 +1,177 @@
+package bimap
+
+import (
+	"bytes"
+	"cmp"
+	"container/list"
+	"encoding/gob"
+	"encoding/json"
+	"io"
+)
+
+// codecEntry is the wire representation used by JSON, gob and the
+// Snapshot/Restore streaming helpers. It is exported to gob via reflection,
+// so an Extra field of interface type (e.g. `any`) requires its concrete
+// types to be registered with gob.Register beforehand, same as any other
+// gob-encoded interface value.
+type codecEntry[K cmp.Ordered, V comparable, E any] struct {
+	Key   K
+	Value V
+	Extra E
+}
+
+// MarshalJSON emits a stable, sorted array of {"Key","Value","Extra"} objects so
+// that two BiMaps with the same contents always serialize identically.
+func (bm *BiMap[K, V, E]) MarshalJSON() ([]byte, error) {
+	bm.mu.RLock()
+	defer bm.mu.RUnlock()
+
+	entries := make([]codecEntry[K, V, E], 0, len(bm.keyToEntry))
+	bm.index.Range(func(key K) bool {
+		e := bm.keyToEntry[key]
+		entries = append(entries, codecEntry[K, V, E]{Key: key, Value: e.Value, Extra: e.Extra})
+		return true
+	})
+	return json.Marshal(entries)
+}
+
+// UnmarshalJSON replaces bm's contents with the entries encoded by
+// MarshalJSON.
+func (bm *BiMap[K, V, E]) UnmarshalJSON(data []byte) error {
+	var entries []codecEntry[K, V, E]
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return err
+	}
+
+	bm.mu.Lock()
+	defer bm.mu.Unlock()
+	bm.resetFromCodecEntriesLocked(entries)
+	return nil
+}
+
+// GobEncode implements gob.GobEncoder, emitting entries in sorted key order.
+func (bm *BiMap[K, V, E]) GobEncode() ([]byte, error) {
+	bm.mu.RLock()
+	defer bm.mu.RUnlock()
+
+	entries := make([]codecEntry[K, V, E], 0, len(bm.keyToEntry))
+	bm.index.Range(func(key K) bool {
+		e := bm.keyToEntry[key]
+		entries = append(entries, codecEntry[K, V, E]{Key: key, Value: e.Value, Extra: e.Extra})
+		return true
+	})
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(entries); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// GobDecode implements gob.GobDecoder, replacing bm's contents.
+func (bm *BiMap[K, V, E]) GobDecode(data []byte) error {
+	var entries []codecEntry[K, V, E]
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&entries); err != nil {
+		return err
+	}
+
+	bm.mu.Lock()
+	defer bm.mu.Unlock()
+	bm.resetFromCodecEntriesLocked(entries)
+	return nil
+}
+
+// Snapshot streams bm's entries to w in sorted key order as a sequence of
+// gob-encoded values. A producer goroutine holds the read lock while it
+// feeds entries into a 64-entry buffered channel; once the buffer is full it
+// keeps the lock while waiting for earlier entries to be written to w.
+func (bm *BiMap[K, V, E]) Snapshot(w io.Writer) error {
+	ch := make(chan codecEntry[K, V, E], 64)
+	done := make(chan struct{})
+
+	go func() {
+		defer close(ch)
+		bm.mu.RLock()
+		defer bm.mu.RUnlock()
+
+		bm.index.Range(func(key K) bool {
+			e := bm.keyToEntry[key]
+			select {
+			case ch <- codecEntry[K, V, E]{Key: key, Value: e.Value, Extra: e.Extra}:
+				return true
+			case <-done:
+				return false
+			}
+		})
+	}()
+	defer close(done)
+
+	enc := gob.NewEncoder(w)
+	for e := range ch {
+		if err := enc.Encode(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Restore replaces bm's contents with the entries streamed by Snapshot. The
+// decoded entries are assembled into fresh maps and index before the write
+// lock is taken; under the lock only the swap and any MaxSize eviction run.
+func (bm *BiMap[K, V, E]) Restore(r io.Reader) error {
+	var entries []codecEntry[K, V, E]
+
+	dec := gob.NewDecoder(r)
+	for {
+		var e codecEntry[K, V, E]
+		err := dec.Decode(&e)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		entries = append(entries, e)
+	}
+
+	fresh := NewBiMap[K, V, E]()
+	for _, e := range entries {
+		fresh.keyToEntry[e.Key] = entry[V, E]{Value: e.Value, Extra: e.Extra}
+		fresh.valueToKey[e.Value] = e.Key
+		fresh.index.Insert(e.Key)
+		fresh.insertElems[e.Key] = fresh.insertOrder.PushBack(e.Key)
+	}
+
+	bm.mu.Lock()
+	defer bm.mu.Unlock()
+	bm.keyToEntry, bm.valueToKey, bm.index = fresh.keyToEntry, fresh.valueToKey, fresh.index
+	bm.expiries, bm.insertOrder, bm.insertElems = fresh.expiries, fresh.insertOrder, fresh.insertElems
+	bm.enforceCapacityLocked()
+	return nil
+}
+
+// resetFromCodecEntriesLocked replaces bm's contents with entries, which must
+// already be in sorted key order as produced by MarshalJSON/GobEncode/
+// Snapshot. The wire format carries no TTL, so every restored entry comes
+// back without one, tracked only in insertOrder for CapacityExceeded
+// eviction; callers that need expiry to survive a round-trip must re-apply
+// it with SetWithTTL. If MaxSize is set and entries exceeds it, the oldest
+// entries (by position in entries) are evicted with OnEvict/CapacityExceeded
+// immediately, same as enforceCapacityLocked does for Set. Must be called
+// with bm.mu held for writing.
+func (bm *BiMap[K, V, E]) resetFromCodecEntriesLocked(entries []codecEntry[K, V, E]) {
+	bm.keyToEntry = make(map[K]entry[V, E], len(entries))
+	bm.valueToKey = make(map[V]K, len(entries))
+	bm.index = newOrderedIndex[K]()
+	bm.expiries = newExpiryHeap[K]()
+	bm.insertOrder = list.New()
+	bm.insertElems = make(map[K]*list.Element, len(entries))
+
+	for _, e := range entries {
+		bm.keyToEntry[e.Key] = entry[V, E]{Value: e.Value, Extra: e.Extra}
+		bm.valueToKey[e.Value] = e.Key
+		bm.index.Insert(e.Key)
+		bm.insertElems[e.Key] = bm.insertOrder.PushBack(e.Key)
+	}
+	bm.enforceCapacityLocked()
+}
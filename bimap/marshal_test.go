@@ -0,0 +1,165 @@
+package bimap
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func newMarshalTestBiMap(insertOrder []int) *BiMap[int, string, int] {
+	bm := NewBiMap[int, string, int]()
+	for _, k := range insertOrder {
+		bm.SetWithExtra(k, "v"+string(rune('0'+k)), k*10)
+	}
+	return bm
+}
+
+func assertMarshalTestBiMapContents(t *testing.T, bm *BiMap[int, string, int], keys []int) {
+	t.Helper()
+	if bm.Len() != len(keys) {
+		t.Fatalf("Len() = %d, want %d", bm.Len(), len(keys))
+	}
+	for _, k := range keys {
+		wantValue := "v" + string(rune('0'+k))
+		value, extra, ok := bm.GetExtra(k)
+		if !ok || value != wantValue || extra != k*10 {
+			t.Fatalf("GetExtra(%d) = (%v, %v, %v), want (%v, %v, true)", k, value, extra, ok, wantValue, k*10)
+		}
+	}
+}
+
+func TestBiMapMarshalJSONRoundTrip(t *testing.T) {
+	src := newMarshalTestBiMap([]int{3, 1, 2})
+
+	data, err := json.Marshal(src)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+
+	dst := NewBiMap[int, string, int]()
+	if err := json.Unmarshal(data, dst); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+
+	assertMarshalTestBiMapContents(t, dst, []int{1, 2, 3})
+}
+
+// TestBiMapMarshalJSONIsDeterministic checks the doc comment's promise that
+// two BiMaps with the same contents serialize byte-identically regardless
+// of insertion order.
+func TestBiMapMarshalJSONIsDeterministic(t *testing.T) {
+	a := newMarshalTestBiMap([]int{1, 2, 3})
+	b := newMarshalTestBiMap([]int{3, 2, 1})
+
+	dataA, err := json.Marshal(a)
+	if err != nil {
+		t.Fatalf("json.Marshal(a) error = %v", err)
+	}
+	dataB, err := json.Marshal(b)
+	if err != nil {
+		t.Fatalf("json.Marshal(b) error = %v", err)
+	}
+
+	if !bytes.Equal(dataA, dataB) {
+		t.Fatalf("json.Marshal differs by insertion order:\na = %s\nb = %s", dataA, dataB)
+	}
+}
+
+func TestBiMapUnmarshalJSONReplacesExistingContents(t *testing.T) {
+	dst := newMarshalTestBiMap([]int{100})
+	src := newMarshalTestBiMap([]int{1, 2})
+
+	data, err := json.Marshal(src)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	if err := json.Unmarshal(data, dst); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+
+	if dst.Contains(100) {
+		t.Fatalf("dst still contains key 100 from before Unmarshal")
+	}
+	assertMarshalTestBiMapContents(t, dst, []int{1, 2})
+}
+
+func TestBiMapGobEncodeDecodeRoundTrip(t *testing.T) {
+	src := newMarshalTestBiMap([]int{3, 1, 2})
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(src); err != nil {
+		t.Fatalf("gob Encode() error = %v", err)
+	}
+
+	dst := NewBiMap[int, string, int]()
+	if err := gob.NewDecoder(&buf).Decode(dst); err != nil {
+		t.Fatalf("gob Decode() error = %v", err)
+	}
+
+	assertMarshalTestBiMapContents(t, dst, []int{1, 2, 3})
+}
+
+// TestBiMapGobEncodeIsDeterministic mirrors the JSON determinism check:
+// GobEncode also emits entries in sorted key order, so two BiMaps with the
+// same contents must encode identically regardless of insertion order.
+func TestBiMapGobEncodeIsDeterministic(t *testing.T) {
+	a := newMarshalTestBiMap([]int{1, 2, 3})
+	b := newMarshalTestBiMap([]int{3, 2, 1})
+
+	dataA, err := a.GobEncode()
+	if err != nil {
+		t.Fatalf("a.GobEncode() error = %v", err)
+	}
+	dataB, err := b.GobEncode()
+	if err != nil {
+		t.Fatalf("b.GobEncode() error = %v", err)
+	}
+
+	if !bytes.Equal(dataA, dataB) {
+		t.Fatalf("GobEncode differs by insertion order:\na = %x\nb = %x", dataA, dataB)
+	}
+}
+
+func TestBiMapSnapshotRestoreRoundTrip(t *testing.T) {
+	src := newMarshalTestBiMap([]int{3, 1, 2})
+
+	var buf bytes.Buffer
+	if err := src.Snapshot(&buf); err != nil {
+		t.Fatalf("Snapshot() error = %v", err)
+	}
+
+	dst := NewBiMap[int, string, int]()
+	if err := dst.Restore(&buf); err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+
+	assertMarshalTestBiMapContents(t, dst, []int{1, 2, 3})
+}
+
+func TestBiMapUnmarshalJSONRejectsInvalidPayload(t *testing.T) {
+	bm := NewBiMap[int, string, int]()
+	if err := json.Unmarshal([]byte("not json"), bm); err == nil {
+		t.Fatalf("Unmarshal(invalid) error = nil, want an error")
+	}
+}
+
+// Sanity check that codecEntry itself round-trips through JSON the way
+// MarshalJSON's doc comment describes the wire format.
+func TestCodecEntryJSONShape(t *testing.T) {
+	src := newMarshalTestBiMap([]int{1})
+	data, err := json.Marshal(src)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+
+	var raw []map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		t.Fatalf("json.Unmarshal(raw) error = %v", err)
+	}
+	want := []map[string]any{{"Key": float64(1), "Value": "v1", "Extra": float64(10)}}
+	if !reflect.DeepEqual(raw, want) {
+		t.Fatalf("raw JSON shape = %+v, want %+v", raw, want)
+	}
+}
@@ -0,0 +1,129 @@
+package bimap
+
+import "sync"
+
+// MultiBiMap maps one key to a set of values while guaranteeing each value
+// points back to exactly one key. It covers the common association-table
+// use case (e.g. user -> set of device IDs, each device owned by one user)
+// that would otherwise force callers to layer their own maps on top of
+// BiMap.
+type MultiBiMap[K, V comparable] struct {
+	mu      sync.RWMutex
+	forward map[K]map[V]struct{}
+	reverse map[V]K
+}
+
+// NewMultiBiMap creates a new MultiBiMap.
+func NewMultiBiMap[K, V comparable]() *MultiBiMap[K, V] {
+	return &MultiBiMap[K, V]{
+		forward: make(map[K]map[V]struct{}),
+		reverse: make(map[V]K),
+	}
+}
+
+// Add associates value with key (thread-safe). If value already belongs to
+// a different key, it is moved: removed from its old key's set before being
+// added to the new one.
+func (m *MultiBiMap[K, V]) Add(key K, value V) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if oldKey, exists := m.reverse[value]; exists {
+		if oldKey == key {
+			return
+		}
+		delete(m.forward[oldKey], value)
+		if len(m.forward[oldKey]) == 0 {
+			delete(m.forward, oldKey)
+		}
+	}
+
+	values, exists := m.forward[key]
+	if !exists {
+		values = make(map[V]struct{})
+		m.forward[key] = values
+	}
+	values[value] = struct{}{}
+	m.reverse[value] = key
+}
+
+// Remove deletes the value from key's set (thread-safe). It is a no-op if
+// value is not currently associated with key.
+func (m *MultiBiMap[K, V]) Remove(key K, value V) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if owner, exists := m.reverse[value]; !exists || owner != key {
+		return
+	}
+
+	delete(m.forward[key], value)
+	if len(m.forward[key]) == 0 {
+		delete(m.forward, key)
+	}
+	delete(m.reverse, value)
+}
+
+// ResetKey replaces key's entire value set with values (thread-safe). Any
+// value previously owned by another key and present in values is moved.
+func (m *MultiBiMap[K, V]) ResetKey(key K, values ...V) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for v := range m.forward[key] {
+		delete(m.reverse, v)
+	}
+	delete(m.forward, key)
+
+	if len(values) == 0 {
+		return
+	}
+
+	newValues := make(map[V]struct{}, len(values))
+	for _, v := range values {
+		if oldKey, exists := m.reverse[v]; exists {
+			delete(m.forward[oldKey], v)
+			if len(m.forward[oldKey]) == 0 {
+				delete(m.forward, oldKey)
+			}
+		}
+		newValues[v] = struct{}{}
+		m.reverse[v] = key
+	}
+	m.forward[key] = newValues
+}
+
+// ValuesOf returns the set of values currently associated with key
+// (thread-safe).
+func (m *MultiBiMap[K, V]) ValuesOf(key K) []V {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	values := m.forward[key]
+	result := make([]V, 0, len(values))
+	for v := range values {
+		result = append(result, v)
+	}
+	return result
+}
+
+// KeyOf returns the key that owns value (thread-safe).
+func (m *MultiBiMap[K, V]) KeyOf(value V) (K, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	key, exists := m.reverse[value]
+	return key, exists
+}
+
+// RangeKey calls f for each value owned by key, stopping early if f returns
+// false (thread-safe). Iteration order is unspecified.
+func (m *MultiBiMap[K, V]) RangeKey(key K, f func(V) bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for v := range m.forward[key] {
+		if !f(v) {
+			return
+		}
+	}
+}
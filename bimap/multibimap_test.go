@@ -0,0 +1,166 @@
+package bimap
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func valuesOfSorted(m *MultiBiMap[string, string], key string) []string {
+	values := m.ValuesOf(key)
+	sort.Strings(values)
+	return values
+}
+
+func TestMultiBiMapAddAndKeyOf(t *testing.T) {
+	m := NewMultiBiMap[string, string]()
+
+	m.Add("user1", "deviceA")
+	m.Add("user1", "deviceB")
+	m.Add("user2", "deviceC")
+
+	if got := valuesOfSorted(m, "user1"); !reflect.DeepEqual(got, []string{"deviceA", "deviceB"}) {
+		t.Fatalf("ValuesOf(user1) = %v, want [deviceA deviceB]", got)
+	}
+	if key, ok := m.KeyOf("deviceA"); !ok || key != "user1" {
+		t.Fatalf("KeyOf(deviceA) = (%v, %v), want (user1, true)", key, ok)
+	}
+	if _, ok := m.KeyOf("never-added"); ok {
+		t.Fatalf("KeyOf(never-added) ok = true, want false")
+	}
+}
+
+// TestMultiBiMapAddMovesValueBetweenKeys checks the guarantee called out in
+// the request: adding a value already owned by another key atomically moves
+// it, never leaving it associated with both.
+func TestMultiBiMapAddMovesValueBetweenKeys(t *testing.T) {
+	m := NewMultiBiMap[string, string]()
+	m.Add("user1", "deviceA")
+	m.Add("user1", "deviceB")
+
+	m.Add("user2", "deviceA") // deviceA moves from user1 to user2
+
+	if got := valuesOfSorted(m, "user1"); !reflect.DeepEqual(got, []string{"deviceB"}) {
+		t.Fatalf("ValuesOf(user1) after move = %v, want [deviceB]", got)
+	}
+	if got := valuesOfSorted(m, "user2"); !reflect.DeepEqual(got, []string{"deviceA"}) {
+		t.Fatalf("ValuesOf(user2) after move = %v, want [deviceA]", got)
+	}
+	if key, ok := m.KeyOf("deviceA"); !ok || key != "user2" {
+		t.Fatalf("KeyOf(deviceA) after move = (%v, %v), want (user2, true)", key, ok)
+	}
+
+	// Re-adding to the same owner must be a no-op.
+	m.Add("user2", "deviceA")
+	if got := valuesOfSorted(m, "user2"); !reflect.DeepEqual(got, []string{"deviceA"}) {
+		t.Fatalf("ValuesOf(user2) after re-Add = %v, want [deviceA]", got)
+	}
+}
+
+// TestMultiBiMapAddDropsKeyWhenItsLastValueIsMovedAway checks that a key's
+// empty forward set is cleaned up, not left dangling.
+func TestMultiBiMapAddDropsKeyWhenItsLastValueIsMovedAway(t *testing.T) {
+	m := NewMultiBiMap[string, string]()
+	m.Add("user1", "deviceA")
+	m.Add("user2", "deviceA") // user1's only value moves away
+
+	if got := m.ValuesOf("user1"); len(got) != 0 {
+		t.Fatalf("ValuesOf(user1) = %v, want empty", got)
+	}
+}
+
+func TestMultiBiMapRemove(t *testing.T) {
+	m := NewMultiBiMap[string, string]()
+	m.Add("user1", "deviceA")
+	m.Add("user1", "deviceB")
+
+	m.Remove("user1", "deviceA")
+
+	if got := valuesOfSorted(m, "user1"); !reflect.DeepEqual(got, []string{"deviceB"}) {
+		t.Fatalf("ValuesOf(user1) after Remove = %v, want [deviceB]", got)
+	}
+	if _, ok := m.KeyOf("deviceA"); ok {
+		t.Fatalf("KeyOf(deviceA) after Remove ok = true, want false")
+	}
+
+	// Removing a value from a key that doesn't own it must be a no-op.
+	m.Remove("user1", "deviceA")
+	m.Remove("user2", "deviceB")
+	if got := valuesOfSorted(m, "user1"); !reflect.DeepEqual(got, []string{"deviceB"}) {
+		t.Fatalf("ValuesOf(user1) after no-op Removes = %v, want [deviceB]", got)
+	}
+
+	// Removing the last value must drop the now-empty key.
+	m.Remove("user1", "deviceB")
+	if got := m.ValuesOf("user1"); len(got) != 0 {
+		t.Fatalf("ValuesOf(user1) after removing last value = %v, want empty", got)
+	}
+}
+
+// TestMultiBiMapResetKeyReleasesDisplacedValues checks that ResetKey both
+// releases key's old values back to reverse (so they can be claimed
+// elsewhere) and steals any of the new values from whoever owned them.
+func TestMultiBiMapResetKeyReleasesDisplacedValues(t *testing.T) {
+	m := NewMultiBiMap[string, string]()
+	m.Add("user1", "deviceA")
+	m.Add("user1", "deviceB")
+	m.Add("user2", "deviceC")
+
+	m.ResetKey("user1", "deviceC", "deviceD")
+
+	if got := valuesOfSorted(m, "user1"); !reflect.DeepEqual(got, []string{"deviceC", "deviceD"}) {
+		t.Fatalf("ValuesOf(user1) after ResetKey = %v, want [deviceC deviceD]", got)
+	}
+	// deviceA and deviceB were released, not left dangling on user1 or orphaned in reverse.
+	if _, ok := m.KeyOf("deviceA"); ok {
+		t.Fatalf("KeyOf(deviceA) after ResetKey ok = true, want false")
+	}
+	if _, ok := m.KeyOf("deviceB"); ok {
+		t.Fatalf("KeyOf(deviceB) after ResetKey ok = true, want false")
+	}
+	// deviceC was stolen from user2.
+	if got := m.ValuesOf("user2"); len(got) != 0 {
+		t.Fatalf("ValuesOf(user2) after deviceC was stolen = %v, want empty", got)
+	}
+	if key, ok := m.KeyOf("deviceC"); !ok || key != "user1" {
+		t.Fatalf("KeyOf(deviceC) = (%v, %v), want (user1, true)", key, ok)
+	}
+
+	m.Add("user3", "deviceA") // deviceA must be free to claim again
+	if key, ok := m.KeyOf("deviceA"); !ok || key != "user3" {
+		t.Fatalf("KeyOf(deviceA) after re-Add = (%v, %v), want (user3, true)", key, ok)
+	}
+}
+
+// TestMultiBiMapResetKeyWithNoValuesClearsKey checks that ResetKey with no
+// values removes key entirely rather than leaving an empty entry behind.
+func TestMultiBiMapResetKeyWithNoValuesClearsKey(t *testing.T) {
+	m := NewMultiBiMap[string, string]()
+	m.Add("user1", "deviceA")
+
+	m.ResetKey("user1")
+
+	if got := m.ValuesOf("user1"); len(got) != 0 {
+		t.Fatalf("ValuesOf(user1) after ResetKey() = %v, want empty", got)
+	}
+	if _, ok := m.KeyOf("deviceA"); ok {
+		t.Fatalf("KeyOf(deviceA) after ResetKey() ok = true, want false")
+	}
+}
+
+// TestMultiBiMapRangeKeyStopsEarly checks RangeKey honors f returning false.
+func TestMultiBiMapRangeKeyStopsEarly(t *testing.T) {
+	m := NewMultiBiMap[string, string]()
+	m.Add("user1", "deviceA")
+	m.Add("user1", "deviceB")
+	m.Add("user1", "deviceC")
+
+	count := 0
+	m.RangeKey("user1", func(v string) bool {
+		count++
+		return false
+	})
+	if count != 1 {
+		t.Fatalf("RangeKey visited %d values after f returned false, want 1", count)
+	}
+}
@@ -0,0 +1,198 @@
+package bimap
+
+import (
+	"cmp"
+	"math/rand"
+	"time"
+)
+
+const (
+	orderedIndexMaxLevel = 32
+	orderedIndexP        = 0.25
+)
+
+// skipListNode is a single node of an orderedIndex. span[i] counts how many
+// nodes (including the destination) are skipped by forward[i], which lets
+// Select/GetRange answer "key at rank N" without a linear scan.
+type skipListNode[K cmp.Ordered] struct {
+	key     K
+	forward []*skipListNode[K]
+	span    []int
+}
+
+// orderedIndex is an indexable skip list keeping keys in sorted order. It
+// replaces a sort-on-insert slice: Insert/Delete are O(log N) instead of
+// O(N log N)/O(N), and Select/GetRange answer rank-based queries in O(log N)
+// by walking the span counters instead of re-deriving a position.
+type orderedIndex[K cmp.Ordered] struct {
+	head   *skipListNode[K]
+	level  int
+	length int
+	rng    *rand.Rand
+}
+
+func newOrderedIndex[K cmp.Ordered]() *orderedIndex[K] {
+	return &orderedIndex[K]{
+		head: &skipListNode[K]{
+			forward: make([]*skipListNode[K], orderedIndexMaxLevel),
+			span:    make([]int, orderedIndexMaxLevel),
+		},
+		level: 1,
+		rng:   rand.New(rand.NewSource(randSeed())),
+	}
+}
+
+func randSeed() int64 {
+	return time.Now().UnixNano()
+}
+
+func (idx *orderedIndex[K]) randomLevel() int {
+	level := 1
+	for level < orderedIndexMaxLevel && idx.rng.Float64() < orderedIndexP {
+		level++
+	}
+	return level
+}
+
+// Len returns the number of keys in the index.
+func (idx *orderedIndex[K]) Len() int {
+	return idx.length
+}
+
+// Insert adds key to the index, preserving sorted order. Inserting a key
+// that already exists is a no-op.
+func (idx *orderedIndex[K]) Insert(key K) {
+	update := make([]*skipListNode[K], orderedIndexMaxLevel)
+	rank := make([]int, orderedIndexMaxLevel)
+
+	node := idx.head
+	for i := idx.level - 1; i >= 0; i-- {
+		if i == idx.level-1 {
+			rank[i] = 0
+		} else {
+			rank[i] = rank[i+1]
+		}
+		for node.forward[i] != nil && node.forward[i].key < key {
+			rank[i] += node.span[i]
+			node = node.forward[i]
+		}
+		update[i] = node
+	}
+
+	if node.forward[0] != nil && node.forward[0].key == key {
+		return // already present
+	}
+
+	newLevel := idx.randomLevel()
+	if newLevel > idx.level {
+		for i := idx.level; i < newLevel; i++ {
+			rank[i] = 0
+			update[i] = idx.head
+			update[i].span[i] = idx.length
+		}
+		idx.level = newLevel
+	}
+
+	newNode := &skipListNode[K]{
+		key:     key,
+		forward: make([]*skipListNode[K], newLevel),
+		span:    make([]int, newLevel),
+	}
+	for i := 0; i < newLevel; i++ {
+		newNode.forward[i] = update[i].forward[i]
+		update[i].forward[i] = newNode
+
+		newNode.span[i] = update[i].span[i] - (rank[0] - rank[i])
+		update[i].span[i] = (rank[0] - rank[i]) + 1
+	}
+	for i := newLevel; i < idx.level; i++ {
+		update[i].span[i]++
+	}
+
+	idx.length++
+}
+
+// Delete removes key from the index, if present.
+func (idx *orderedIndex[K]) Delete(key K) {
+	update := make([]*skipListNode[K], orderedIndexMaxLevel)
+
+	node := idx.head
+	for i := idx.level - 1; i >= 0; i-- {
+		for node.forward[i] != nil && node.forward[i].key < key {
+			node = node.forward[i]
+		}
+		update[i] = node
+	}
+
+	target := node.forward[0]
+	if target == nil || target.key != key {
+		return
+	}
+
+	for i := 0; i < idx.level; i++ {
+		if update[i].forward[i] == target {
+			update[i].span[i] += target.span[i] - 1
+			update[i].forward[i] = target.forward[i]
+		} else {
+			update[i].span[i]--
+		}
+	}
+
+	for idx.level > 1 && idx.head.forward[idx.level-1] == nil {
+		idx.level--
+	}
+	idx.length--
+}
+
+// Select returns the key at the given 0-based rank.
+func (idx *orderedIndex[K]) Select(rank int) (K, bool) {
+	if rank < 0 || rank >= idx.length {
+		var zero K
+		return zero, false
+	}
+
+	node := idx.head
+	traversed := -1
+	for i := idx.level - 1; i >= 0; i-- {
+		for node.forward[i] != nil && traversed+node.span[i] <= rank {
+			traversed += node.span[i]
+			node = node.forward[i]
+		}
+	}
+	return node.key, true
+}
+
+// Range calls f for every key in sorted order, stopping early if f returns
+// false.
+func (idx *orderedIndex[K]) Range(f func(K) bool) {
+	for node := idx.head.forward[0]; node != nil; node = node.forward[0] {
+		if !f(node.key) {
+			return
+		}
+	}
+}
+
+// GetRange returns up to limit keys in sorted order starting at the given
+// 0-based rank.
+func (idx *orderedIndex[K]) GetRange(start, limit int) []K {
+	if start < 0 || start >= idx.length || limit <= 0 {
+		return nil
+	}
+
+	node := idx.head
+	traversed := -1
+	for i := idx.level - 1; i >= 0; i-- {
+		for node.forward[i] != nil && traversed+node.span[i] < start {
+			traversed += node.span[i]
+			node = node.forward[i]
+		}
+	}
+	node = node.forward[0]
+
+	result := make([]K, 0, min(limit, idx.length-start))
+	for node != nil && len(result) < limit {
+		result = append(result, node.key)
+		node = node.forward[0]
+	}
+	return result
+}
@@ -0,0 +1,200 @@
+package bimap
+
+import (
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// referenceKeys returns the keys of want sorted ascending, matching the
+// order orderedIndex is expected to produce.
+func referenceKeys(want map[int]bool) []int {
+	keys := make([]int, 0, len(want))
+	for k := range want {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+	return keys
+}
+
+// TestOrderedIndexInsertDeleteAgainstReference drives random Insert/Delete
+// calls against both an orderedIndex and a plain map, and checks Len, Range,
+// Select and GetRange all agree with the reference after every mutation.
+func TestOrderedIndexInsertDeleteAgainstReference(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	idx := newOrderedIndex[int]()
+	want := make(map[int]bool)
+
+	for i := 0; i < 5000; i++ {
+		key := rng.Intn(800)
+		if rng.Intn(3) == 0 && len(want) > 0 {
+			keys := referenceKeys(want)
+			key = keys[rng.Intn(len(keys))]
+			delete(want, key)
+			idx.Delete(key)
+		} else {
+			want[key] = true
+			idx.Insert(key)
+		}
+
+		if idx.Len() != len(want) {
+			t.Fatalf("after op %d: Len() = %d, want %d", i, idx.Len(), len(want))
+		}
+	}
+
+	wantKeys := referenceKeys(want)
+
+	var gotRange []int
+	idx.Range(func(k int) bool {
+		gotRange = append(gotRange, k)
+		return true
+	})
+	if !reflect.DeepEqual(gotRange, wantKeys) {
+		t.Fatalf("Range() = %v, want %v", gotRange, wantKeys)
+	}
+
+	for rank, want := range wantKeys {
+		got, ok := idx.Select(rank)
+		if !ok || got != want {
+			t.Fatalf("Select(%d) = (%v, %v), want (%v, true)", rank, got, ok, want)
+		}
+	}
+	if _, ok := idx.Select(len(wantKeys)); ok {
+		t.Fatalf("Select(%d) (out of range) returned ok=true", len(wantKeys))
+	}
+
+	for start := 0; start < len(wantKeys); start += 7 {
+		for _, limit := range []int{1, 3, len(wantKeys)} {
+			got := idx.GetRange(start, limit)
+			end := start + limit
+			if end > len(wantKeys) {
+				end = len(wantKeys)
+			}
+			want := wantKeys[start:end]
+			if !reflect.DeepEqual(got, want) {
+				t.Fatalf("GetRange(%d, %d) = %v, want %v", start, limit, got, want)
+			}
+		}
+	}
+}
+
+// TestOrderedIndexRangeStopsEarly checks Range honors f returning false.
+func TestOrderedIndexRangeStopsEarly(t *testing.T) {
+	idx := newOrderedIndex[int]()
+	for _, k := range []int{5, 1, 3, 2, 4} {
+		idx.Insert(k)
+	}
+
+	var seen []int
+	idx.Range(func(k int) bool {
+		seen = append(seen, k)
+		return len(seen) < 3
+	})
+	if want := []int{1, 2, 3}; !reflect.DeepEqual(seen, want) {
+		t.Fatalf("Range stopped at %v, want %v", seen, want)
+	}
+}
+
+// TestOrderedIndexInsertDuplicateIsNoOp checks that inserting an existing
+// key leaves Len and ordering unchanged.
+func TestOrderedIndexInsertDuplicateIsNoOp(t *testing.T) {
+	idx := newOrderedIndex[int]()
+	for _, k := range []int{10, 20, 30} {
+		idx.Insert(k)
+	}
+	idx.Insert(20)
+	if idx.Len() != 3 {
+		t.Fatalf("Len() = %d after duplicate insert, want 3", idx.Len())
+	}
+
+	var got []int
+	idx.Range(func(k int) bool {
+		got = append(got, k)
+		return true
+	})
+	if want := []int{10, 20, 30}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("Range() = %v, want %v", got, want)
+	}
+}
+
+// TestOrderedIndexGetRangeEmptyOrOutOfBounds checks GetRange's edge cases.
+func TestOrderedIndexGetRangeEmptyOrOutOfBounds(t *testing.T) {
+	idx := newOrderedIndex[int]()
+	if got := idx.GetRange(0, 10); got != nil {
+		t.Fatalf("GetRange on empty index = %v, want nil", got)
+	}
+
+	idx.Insert(1)
+	if got := idx.GetRange(-1, 10); got != nil {
+		t.Fatalf("GetRange(-1, 10) = %v, want nil", got)
+	}
+	if got := idx.GetRange(5, 10); got != nil {
+		t.Fatalf("GetRange(5, 10) = %v, want nil", got)
+	}
+	if got := idx.GetRange(0, 0); got != nil {
+		t.Fatalf("GetRange(0, 0) = %v, want nil", got)
+	}
+}
+
+// sortedSliceIndex is a sorted slice that inserts by binary search and copy,
+// a cheaper stand-in for the re-sort-on-every-insert slice orderedIndex
+// replaced, kept here only so BenchmarkOrderedIndexInsert can show the speedup.
+type sortedSliceIndex struct {
+	keys []int
+}
+
+func (s *sortedSliceIndex) Insert(key int) {
+	i := sort.SearchInts(s.keys, key)
+	if i < len(s.keys) && s.keys[i] == key {
+		return
+	}
+	s.keys = append(s.keys, 0)
+	copy(s.keys[i+1:], s.keys[i:])
+	s.keys[i] = key
+}
+
+// BenchmarkOrderedIndexInsert compares orderedIndex's skip list against the
+// sorted slice in sortedSliceIndex, at increasing entry counts.
+func BenchmarkOrderedIndexInsert(b *testing.B) {
+	for _, n := range []int{1e3, 1e5, 1e6} {
+		b.Run("skiplist/"+itoa(n), func(b *testing.B) {
+			rng := rand.New(rand.NewSource(1))
+			keys := make([]int, n)
+			for i := range keys {
+				keys[i] = rng.Int()
+			}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				idx := newOrderedIndex[int]()
+				for _, k := range keys {
+					idx.Insert(k)
+				}
+			}
+		})
+		b.Run("sortedSlice/"+itoa(n), func(b *testing.B) {
+			rng := rand.New(rand.NewSource(1))
+			keys := make([]int, n)
+			for i := range keys {
+				keys[i] = rng.Int()
+			}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				s := &sortedSliceIndex{}
+				for _, k := range keys {
+					s.Insert(k)
+				}
+			}
+		})
+	}
+}
+
+func itoa(n int) string {
+	if n >= 1e6 {
+		return "1e6"
+	}
+	if n >= 1e5 {
+		return "1e5"
+	}
+	return "1e3"
+}
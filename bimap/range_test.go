@@ -0,0 +1,158 @@
+package bimap
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func populatedBiMap() *BiMap[int, string, int] {
+	bm := NewBiMap[int, string, int]()
+	for i := 3; i >= 1; i-- { // insert out of order; iteration must still be sorted by key
+		bm.SetWithExtra(i, "v"+string(rune('0'+i)), i*10)
+	}
+	return bm
+}
+
+func TestBiMapRangeSortedOrder(t *testing.T) {
+	bm := populatedBiMap()
+
+	var gotKeys []int
+	var gotValues []string
+	bm.Range(func(key int, value string, extra int) bool {
+		gotKeys = append(gotKeys, key)
+		gotValues = append(gotValues, value)
+		if extra != key*10 {
+			t.Fatalf("Range extra for key %d = %d, want %d", key, extra, key*10)
+		}
+		return true
+	})
+
+	if want := []int{1, 2, 3}; !reflect.DeepEqual(gotKeys, want) {
+		t.Fatalf("Range keys = %v, want %v", gotKeys, want)
+	}
+	if want := []string{"v1", "v2", "v3"}; !reflect.DeepEqual(gotValues, want) {
+		t.Fatalf("Range values = %v, want %v", gotValues, want)
+	}
+}
+
+func TestBiMapRangeStopsEarly(t *testing.T) {
+	bm := populatedBiMap()
+
+	var seen []int
+	bm.Range(func(key int, value string, extra int) bool {
+		seen = append(seen, key)
+		return key < 2
+	})
+	if want := []int{1, 2}; !reflect.DeepEqual(seen, want) {
+		t.Fatalf("Range stopped at %v, want %v", seen, want)
+	}
+}
+
+func TestBiMapAllSortedOrderAndEarlyStop(t *testing.T) {
+	bm := populatedBiMap()
+
+	var gotKeys []int
+	for key, item := range bm.All() {
+		if item.Key != key || item.Extra != key*10 {
+			t.Fatalf("All() yielded key=%d, item=%+v, want item.Key==key and item.Extra==key*10", key, item)
+		}
+		gotKeys = append(gotKeys, key)
+	}
+	if want := []int{1, 2, 3}; !reflect.DeepEqual(gotKeys, want) {
+		t.Fatalf("All() keys = %v, want %v", gotKeys, want)
+	}
+
+	gotKeys = nil
+	for key := range bm.All() {
+		gotKeys = append(gotKeys, key)
+		if key == 2 {
+			break // range-over-func break must stop the underlying Range early
+		}
+	}
+	if want := []int{1, 2}; !reflect.DeepEqual(gotKeys, want) {
+		t.Fatalf("All() with early break = %v, want %v", gotKeys, want)
+	}
+}
+
+func TestBiMapKeysSortedOrder(t *testing.T) {
+	bm := populatedBiMap()
+
+	var got []int
+	for k := range bm.Keys() {
+		got = append(got, k)
+	}
+	if want := []int{1, 2, 3}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("Keys() = %v, want %v", got, want)
+	}
+}
+
+func TestBiMapValuesSortedByKeyOrder(t *testing.T) {
+	bm := populatedBiMap()
+
+	var got []string
+	for v := range bm.Values() {
+		got = append(got, v)
+	}
+	if want := []string{"v1", "v2", "v3"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("Values() = %v, want %v", got, want)
+	}
+}
+
+func TestBiMapEmptyIteratorsYieldNothing(t *testing.T) {
+	bm := NewBiMap[int, string, int]()
+
+	bm.Range(func(int, string, int) bool {
+		t.Fatalf("Range called f on an empty BiMap")
+		return true
+	})
+	for range bm.All() {
+		t.Fatalf("All() yielded on an empty BiMap")
+	}
+	for range bm.Keys() {
+		t.Fatalf("Keys() yielded on an empty BiMap")
+	}
+	for range bm.Values() {
+		t.Fatalf("Values() yielded on an empty BiMap")
+	}
+}
+
+// TestBiMapRangeHoldsReadLockForDurationOfYield checks the documented
+// contract that Range (and, by the same implementation, All/Keys/Values)
+// keeps the read lock held while f runs: a concurrent Set must block until
+// the in-progress Range call returns.
+func TestBiMapRangeHoldsReadLockForDurationOfYield(t *testing.T) {
+	bm := NewBiMap[int, string, int]()
+	bm.Set(1, "v1")
+
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	done := make(chan struct{})
+
+	go func() {
+		bm.Range(func(key int, value string, extra int) bool {
+			close(entered)
+			<-release
+			return true
+		})
+		close(done)
+	}()
+
+	<-entered
+
+	setDone := make(chan struct{})
+	go func() {
+		bm.Set(2, "v2") // must block: Range is holding the read lock
+		close(setDone)
+	}()
+
+	select {
+	case <-setDone:
+		t.Fatalf("Set completed while Range's yield was still in flight")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	close(release)
+	<-done
+	<-setDone // must complete promptly now that Range released the lock
+}
@@ -0,0 +1,286 @@
+package bimap
+
+import (
+	"fmt"
+	"hash/fnv"
+	"runtime"
+	"sort"
+	"sync"
+)
+
+// defaultShardCount is used by NewShardedBiMap when neither the given shard
+// count nor runtime.GOMAXPROCS(0) is positive.
+const defaultShardCount = 16
+
+type shardedEntry[V comparable, E any] struct {
+	Value V
+	Extra E
+}
+
+// forwardShard holds a slice of the key -> entry map.
+type forwardShard[K comparable, V comparable, E any] struct {
+	mu sync.RWMutex
+	m  map[K]shardedEntry[V, E]
+}
+
+// reverseShard holds a slice of the value -> key map.
+type reverseShard[K comparable, V comparable] struct {
+	mu sync.RWMutex
+	m  map[V]K
+}
+
+// ShardedBiMap is a bidirectional map partitioned across N shards on each
+// side (forward and reverse) to cut lock contention under concurrent
+// read/write-heavy workloads, at the cost of dropping BiMap's ordered-key
+// index: there is no GetWithRange equivalent. K, V and E follow the same
+// convention as BiMap, except ShardedBiMap only needs K to be comparable,
+// not ordered, since there is no index to keep sorted. Forward and reverse
+// shard indices are picked independently by hashOf(key)%N / hashOf(value)%N,
+// so a single Set can touch up to two shards; displacing an existing
+// key/value pair can touch up to four. Multi-shard operations always
+// acquire their locks in a fixed (index, shard-kind) order to avoid
+// deadlocks.
+type ShardedBiMap[K comparable, V comparable, E any] struct {
+	n       int
+	forward []*forwardShard[K, V, E]
+	reverse []*reverseShard[K, V]
+}
+
+// NewShardedBiMap creates a ShardedBiMap with shardCount shards per side. If
+// shardCount is omitted or <= 0, it defaults to runtime.GOMAXPROCS(0), or 16
+// if that is 0.
+func NewShardedBiMap[K comparable, V comparable, E any](shardCount ...int) *ShardedBiMap[K, V, E] {
+	n := 0
+	if len(shardCount) > 0 {
+		n = shardCount[0]
+	}
+	if n <= 0 {
+		n = runtime.GOMAXPROCS(0)
+	}
+	if n <= 0 {
+		n = defaultShardCount
+	}
+
+	sm := &ShardedBiMap[K, V, E]{
+		n:       n,
+		forward: make([]*forwardShard[K, V, E], n),
+		reverse: make([]*reverseShard[K, V], n),
+	}
+	for i := 0; i < n; i++ {
+		sm.forward[i] = &forwardShard[K, V, E]{m: make(map[K]shardedEntry[V, E])}
+		sm.reverse[i] = &reverseShard[K, V]{m: make(map[V]K)}
+	}
+	return sm
+}
+
+// StringShardedBiMap is the string/string/any instantiation used by callers
+// that don't need typed extras or non-string keys.
+type StringShardedBiMap = ShardedBiMap[string, string, any]
+
+// NewStringShardedBiMap creates a new StringShardedBiMap.
+func NewStringShardedBiMap(shardCount ...int) *StringShardedBiMap {
+	return NewShardedBiMap[string, string, any](shardCount...)
+}
+
+// hashOf hashes any comparable value into a shard bucket. It goes through
+// fmt.Sprintf rather than a type-specific hash since K and V are only
+// constrained to comparable, not to any hashable-bytes interface; that
+// costs an allocation per Set/Get but keeps ShardedBiMap generic like the
+// rest of the package.
+func hashOf(v any) uint32 {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(fmt.Sprintf("%v", v)))
+	return h.Sum32()
+}
+
+func (sm *ShardedBiMap[K, V, E]) forwardIndex(key K) int {
+	return int(hashOf(key) % uint32(sm.n))
+}
+
+func (sm *ShardedBiMap[K, V, E]) reverseIndex(value V) int {
+	return int(hashOf(value) % uint32(sm.n))
+}
+
+// shardLock is one (kind, index) lock to acquire as part of a multi-shard
+// operation. kind orders forward shards before reverse shards at the same
+// index, giving every caller the same total order regardless of which
+// keys/values happen to hash together.
+type shardLock struct {
+	kind int // 0 = forward, 1 = reverse
+	idx  int
+	mu   *sync.RWMutex
+}
+
+func lockAllWrite(locks []shardLock) {
+	sort.Slice(locks, func(i, j int) bool {
+		if locks[i].idx != locks[j].idx {
+			return locks[i].idx < locks[j].idx
+		}
+		return locks[i].kind < locks[j].kind
+	})
+	seen := make(map[*sync.RWMutex]bool, len(locks))
+	for _, l := range locks {
+		if seen[l.mu] {
+			continue
+		}
+		seen[l.mu] = true
+		l.mu.Lock()
+	}
+}
+
+func unlockAllWrite(locks []shardLock) {
+	seen := make(map[*sync.RWMutex]bool, len(locks))
+	for _, l := range locks {
+		if seen[l.mu] {
+			continue
+		}
+		seen[l.mu] = true
+		l.mu.Unlock()
+	}
+}
+
+// Set adds a key-value pair to the ShardedBiMap (thread-safe).
+func (sm *ShardedBiMap[K, V, E]) Set(key K, value V) {
+	var extra E
+	sm.SetWithExtra(key, value, extra)
+}
+
+// SetWithExtra adds a key-value pair with an extra payload (thread-safe). If
+// key or value already belongs to a different pair, that pair is displaced,
+// which may require locking up to four shards.
+func (sm *ShardedBiMap[K, V, E]) SetWithExtra(key K, value V, extra E) {
+	fIdx := sm.forwardIndex(key)
+	rIdx := sm.reverseIndex(value)
+
+	for {
+		// Peek (unlocked) at what else might need to move so we know the
+		// full lock set up front; re-verified once everything is locked.
+		fShard := sm.forward[fIdx]
+		rShard := sm.reverse[rIdx]
+
+		fShard.mu.RLock()
+		oldEntry, hadKey := fShard.m[key]
+		fShard.mu.RUnlock()
+
+		rShard.mu.RLock()
+		oldKeyForValue, hadValue := rShard.m[value]
+		rShard.mu.RUnlock()
+
+		locks := []shardLock{
+			{kind: 0, idx: fIdx, mu: &fShard.mu},
+			{kind: 1, idx: rIdx, mu: &rShard.mu},
+		}
+		if hadKey && oldEntry.Value != value {
+			locks = append(locks, shardLock{kind: 1, idx: sm.reverseIndex(oldEntry.Value), mu: &sm.reverse[sm.reverseIndex(oldEntry.Value)].mu})
+		}
+		if hadValue && oldKeyForValue != key {
+			locks = append(locks, shardLock{kind: 0, idx: sm.forwardIndex(oldKeyForValue), mu: &sm.forward[sm.forwardIndex(oldKeyForValue)].mu})
+		}
+
+		lockAllWrite(locks)
+
+		// Re-check nothing raced between the peek and the lock.
+		curEntry, curHadKey := fShard.m[key]
+		curKeyForValue, curHadValue := rShard.m[value]
+		if curHadKey != hadKey || curHadKey && curEntry.Value != oldEntry.Value ||
+			curHadValue != hadValue || curHadValue && curKeyForValue != oldKeyForValue {
+			unlockAllWrite(locks)
+			continue
+		}
+
+		if hadKey && oldEntry.Value != value {
+			delete(sm.reverse[sm.reverseIndex(oldEntry.Value)].m, oldEntry.Value)
+		}
+		if hadValue && oldKeyForValue != key {
+			delete(sm.forward[sm.forwardIndex(oldKeyForValue)].m, oldKeyForValue)
+		}
+
+		fShard.m[key] = shardedEntry[V, E]{Value: value, Extra: extra}
+		rShard.m[value] = key
+
+		unlockAllWrite(locks)
+		return
+	}
+}
+
+// Get returns the value for a given key (thread-safe).
+func (sm *ShardedBiMap[K, V, E]) Get(key K) (V, bool) {
+	shard := sm.forward[sm.forwardIndex(key)]
+	shard.mu.RLock()
+	defer shard.mu.RUnlock()
+	e, exists := shard.m[key]
+	return e.Value, exists
+}
+
+// GetExtra returns the value and extra payload for a given key
+// (thread-safe).
+func (sm *ShardedBiMap[K, V, E]) GetExtra(key K) (V, E, bool) {
+	shard := sm.forward[sm.forwardIndex(key)]
+	shard.mu.RLock()
+	defer shard.mu.RUnlock()
+	e, exists := shard.m[key]
+	if !exists {
+		var zero V
+		var zeroExtra E
+		return zero, zeroExtra, false
+	}
+	return e.Value, e.Extra, true
+}
+
+// GetByValue returns the key for a given value (thread-safe).
+func (sm *ShardedBiMap[K, V, E]) GetByValue(value V) (K, bool) {
+	shard := sm.reverse[sm.reverseIndex(value)]
+	shard.mu.RLock()
+	defer shard.mu.RUnlock()
+	key, exists := shard.m[value]
+	return key, exists
+}
+
+// Delete removes a key-value pair from the ShardedBiMap (thread-safe).
+func (sm *ShardedBiMap[K, V, E]) Delete(key K) {
+	fShard := sm.forward[sm.forwardIndex(key)]
+
+	fShard.mu.RLock()
+	e, exists := fShard.m[key]
+	fShard.mu.RUnlock()
+	if !exists {
+		return
+	}
+
+	for {
+		rShard := sm.reverse[sm.reverseIndex(e.Value)]
+		locks := []shardLock{
+			{kind: 0, idx: sm.forwardIndex(key), mu: &fShard.mu},
+			{kind: 1, idx: sm.reverseIndex(e.Value), mu: &rShard.mu},
+		}
+		lockAllWrite(locks)
+
+		cur, stillExists := fShard.m[key]
+		if !stillExists {
+			unlockAllWrite(locks)
+			return
+		}
+		if cur.Value != e.Value {
+			// Value changed since the peek; restart against the new value.
+			e = cur
+			unlockAllWrite(locks)
+			continue
+		}
+
+		delete(fShard.m, key)
+		delete(rShard.m, e.Value)
+		unlockAllWrite(locks)
+		return
+	}
+}
+
+// Len returns the number of mappings (thread-safe).
+func (sm *ShardedBiMap[K, V, E]) Len() int {
+	total := 0
+	for _, shard := range sm.forward {
+		shard.mu.RLock()
+		total += len(shard.m)
+		shard.mu.RUnlock()
+	}
+	return total
+}
@@ -0,0 +1,198 @@
+package bimap
+
+import (
+	"math/rand"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func TestShardedBiMapSetGetDeleteBasic(t *testing.T) {
+	sm := NewShardedBiMap[string, string, int](4)
+
+	sm.SetWithExtra("k1", "v1", 1)
+	if v, ok := sm.Get("k1"); !ok || v != "v1" {
+		t.Fatalf("Get(k1) = (%v, %v), want (v1, true)", v, ok)
+	}
+	if k, ok := sm.GetByValue("v1"); !ok || k != "k1" {
+		t.Fatalf("GetByValue(v1) = (%v, %v), want (k1, true)", k, ok)
+	}
+	if _, _, ok := sm.GetExtra("k1"); !ok {
+		t.Fatalf("GetExtra(k1) ok = false, want true")
+	}
+
+	// Re-pointing k1 at v2 must displace the old v1 mapping on both sides.
+	sm.Set("k1", "v2")
+	if _, ok := sm.GetByValue("v1"); ok {
+		t.Fatalf("GetByValue(v1) still found after k1 was repointed to v2")
+	}
+	if v, ok := sm.Get("k1"); !ok || v != "v2" {
+		t.Fatalf("Get(k1) = (%v, %v), want (v2, true)", v, ok)
+	}
+
+	// Setting k2 to v2 must displace k1's claim on v2.
+	sm.Set("k2", "v2")
+	if _, ok := sm.Get("k1"); ok {
+		t.Fatalf("Get(k1) still found after v2 was repointed to k2")
+	}
+	if k, ok := sm.GetByValue("v2"); !ok || k != "k2" {
+		t.Fatalf("GetByValue(v2) = (%v, %v), want (k2, true)", k, ok)
+	}
+
+	if sm.Len() != 1 {
+		t.Fatalf("Len() = %d, want 1", sm.Len())
+	}
+
+	sm.Delete("k2")
+	if sm.Len() != 0 {
+		t.Fatalf("Len() = %d after Delete, want 0", sm.Len())
+	}
+	if _, ok := sm.GetByValue("v2"); ok {
+		t.Fatalf("GetByValue(v2) still found after Delete(k2)")
+	}
+}
+
+// TestShardedBiMapGetExtraRoundTrip checks that a typed Extra survives a
+// SetWithExtra/GetExtra round trip, the same guarantee chunk0-1 covers for
+// BiMap.
+func TestShardedBiMapGetExtraRoundTrip(t *testing.T) {
+	type payload struct {
+		Count int
+	}
+
+	sm := NewShardedBiMap[string, string, payload](4)
+	sm.SetWithExtra("k1", "v1", payload{Count: 7})
+
+	value, extra, ok := sm.GetExtra("k1")
+	if !ok || value != "v1" || extra != (payload{Count: 7}) {
+		t.Fatalf("GetExtra(k1) = (%v, %+v, %v), want (v1, {Count:7}, true)", value, extra, ok)
+	}
+
+	_, extra, ok = sm.GetExtra("missing")
+	if ok || extra != (payload{}) {
+		t.Fatalf("GetExtra(missing) = (_, %+v, %v), want (_, zero value, false)", extra, ok)
+	}
+}
+
+// checkShardedBiMapInvariant walks every shard and fails t if the forward
+// and reverse maps ever disagree about a mapping.
+func checkShardedBiMapInvariant(t *testing.T, sm *ShardedBiMap[string, string, int]) {
+	t.Helper()
+
+	forwardCount := 0
+	for _, shard := range sm.forward {
+		shard.mu.RLock()
+		for k, e := range shard.m {
+			forwardCount++
+			rShard := sm.reverse[sm.reverseIndex(e.Value)]
+			rShard.mu.RLock()
+			rk, ok := rShard.m[e.Value]
+			rShard.mu.RUnlock()
+			if !ok || rk != k {
+				t.Errorf("forward[%q]=%q has no matching reverse entry (got rk=%q, ok=%v)", k, e.Value, rk, ok)
+			}
+		}
+		shard.mu.RUnlock()
+	}
+
+	reverseCount := 0
+	for _, shard := range sm.reverse {
+		shard.mu.RLock()
+		reverseCount += len(shard.m)
+		shard.mu.RUnlock()
+	}
+
+	if forwardCount != reverseCount {
+		t.Errorf("forward side has %d entries, reverse side has %d", forwardCount, reverseCount)
+	}
+	if got := sm.Len(); got != forwardCount {
+		t.Errorf("Len() = %d, want %d", got, forwardCount)
+	}
+}
+
+// TestShardedBiMapConcurrentSetDeleteHoldsInvariant hammers a small keyspace
+// from many goroutines (so keys and values collide across shards, forcing
+// the multi-shard lock-ordering path) and checks the forward/reverse maps
+// never disagree once the dust settles. Run with -race to catch deadlocks
+// and data races, not just lost updates.
+func TestShardedBiMapConcurrentSetDeleteHoldsInvariant(t *testing.T) {
+	sm := NewShardedBiMap[string, string, int](4)
+	const goroutines = 16
+	const opsPerGoroutine = 2000
+	const keyspace = 12
+
+	var wg sync.WaitGroup
+	wg.Add(goroutines)
+	for g := 0; g < goroutines; g++ {
+		go func(seed int64) {
+			defer wg.Done()
+			rng := rand.New(rand.NewSource(seed))
+			for i := 0; i < opsPerGoroutine; i++ {
+				key := "k" + strconv.Itoa(rng.Intn(keyspace))
+				value := "v" + strconv.Itoa(rng.Intn(keyspace))
+				switch rng.Intn(3) {
+				case 0:
+					sm.Set(key, value)
+				case 1:
+					sm.Delete(key)
+				default:
+					sm.Get(key)
+					sm.GetByValue(value)
+				}
+			}
+		}(int64(g) + 1)
+	}
+	wg.Wait()
+
+	checkShardedBiMapInvariant(t, sm)
+}
+
+// BenchmarkShardedBiMapConcurrent and BenchmarkStringBiMapConcurrent measure
+// mixed store/delete/lookup throughput under contention from 12 concurrent
+// goroutines, showing the payoff of splitting the single mutex pair in
+// StringBiMap into per-shard locks.
+func benchmarkConcurrentMixedOps(b *testing.B, set func(key, value string), get func(key string), del func(key string)) {
+	const goroutines = 12
+	const keyspace = 1000
+
+	b.ResetTimer()
+	var wg sync.WaitGroup
+	perGoroutine := (b.N + goroutines - 1) / goroutines
+	wg.Add(goroutines)
+	for g := 0; g < goroutines; g++ {
+		go func(seed int64) {
+			defer wg.Done()
+			rng := rand.New(rand.NewSource(seed))
+			for i := 0; i < perGoroutine; i++ {
+				key := "k" + strconv.Itoa(rng.Intn(keyspace))
+				switch rng.Intn(3) {
+				case 0:
+					set(key, "v"+strconv.Itoa(rng.Intn(keyspace)))
+				case 1:
+					del(key)
+				default:
+					get(key)
+				}
+			}
+		}(int64(g) + 1)
+	}
+	wg.Wait()
+}
+
+func BenchmarkShardedBiMapConcurrent(b *testing.B) {
+	sm := NewShardedBiMap[string, string, int](16)
+	benchmarkConcurrentMixedOps(b,
+		func(key, value string) { sm.Set(key, value) },
+		func(key string) { sm.Get(key) },
+		func(key string) { sm.Delete(key) },
+	)
+}
+
+func BenchmarkStringBiMapConcurrent(b *testing.B) {
+	bm := NewStringBiMap()
+	benchmarkConcurrentMixedOps(b,
+		func(key, value string) { bm.Set(key, value) },
+		func(key string) { bm.Get(key) },
+		func(key string) { bm.Delete(key) },
+	)
+}
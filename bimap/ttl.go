@@ -0,0 +1,285 @@
+package bimap
+
+import (
+	"cmp"
+	"container/heap"
+	"time"
+)
+
+// EvictReason describes why an entry left a BiMap via its OnEvict callback.
+type EvictReason int
+
+const (
+	// Expired means the entry's TTL elapsed.
+	Expired EvictReason = iota
+	// Replaced means a Set call overwrote the entry's key or value.
+	Replaced
+	// Deleted means Delete was called explicitly.
+	Deleted
+	// CapacityExceeded means the entry was evicted to stay within MaxSize.
+	CapacityExceeded
+)
+
+func (r EvictReason) String() string {
+	switch r {
+	case Expired:
+		return "Expired"
+	case Replaced:
+		return "Replaced"
+	case Deleted:
+		return "Deleted"
+	case CapacityExceeded:
+		return "CapacityExceeded"
+	default:
+		return "Unknown"
+	}
+}
+
+// Options configures a BiMap created with NewBiMapWithOptions.
+type Options[K cmp.Ordered, V comparable, E any] struct {
+	// MaxSize caps the number of entries; 0 means unbounded. Once the cap is
+	// reached, inserting a new key evicts the entry with the earliest TTL
+	// expiry, or the oldest-inserted entry if none has a TTL.
+	MaxSize int
+	// DefaultTTL is applied by Set/SetWithExtra; 0 means those entries never
+	// expire. SetWithTTL can still set a per-entry TTL regardless of this.
+	DefaultTTL time.Duration
+	// OnEvict, if set, is called synchronously whenever an entry expires, is
+	// replaced, is deleted or is evicted to stay within MaxSize. Clear and the
+	// JSON/gob/Restore decoders discard existing entries without calling it.
+	OnEvict func(key K, value V, extra E, reason EvictReason)
+}
+
+// NewBiMapWithOptions creates a BiMap configured for TTL expiry and/or a
+// size cap.
+func NewBiMapWithOptions[K cmp.Ordered, V comparable, E any](opts Options[K, V, E]) *BiMap[K, V, E] {
+	bm := NewBiMap[K, V, E]()
+	bm.maxSize = opts.MaxSize
+	bm.defaultTTL = opts.DefaultTTL
+	bm.onEvict = opts.OnEvict
+	return bm
+}
+
+// SetWithTTL adds a key-value pair that expires after ttl, overriding any
+// DefaultTTL configured via NewBiMapWithOptions. A ttl of 0 means the entry
+// never expires.
+func (bm *BiMap[K, V, E]) SetWithTTL(key K, value V, extra E, ttl time.Duration) {
+	bm.mu.Lock()
+	defer bm.mu.Unlock()
+	bm.setLocked(key, value, extra, ttl)
+}
+
+// StartJanitor starts a background goroutine that sweeps expired entries
+// every interval, in addition to the lazy sweep already performed on every
+// mutation. It is a no-op if a janitor is already running. Stop it with
+// Close.
+func (bm *BiMap[K, V, E]) StartJanitor(interval time.Duration) {
+	bm.mu.Lock()
+	defer bm.mu.Unlock()
+
+	if bm.janitorStop != nil {
+		return
+	}
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	bm.janitorStop = stop
+	bm.janitorDone = done
+
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				bm.mu.Lock()
+				bm.sweepExpiredLocked()
+				bm.mu.Unlock()
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+// Close stops the background janitor started by StartJanitor, if any, and
+// waits for it to exit.
+func (bm *BiMap[K, V, E]) Close() {
+	bm.mu.Lock()
+	stop := bm.janitorStop
+	done := bm.janitorDone
+	bm.janitorStop = nil
+	bm.janitorDone = nil
+	bm.mu.Unlock()
+
+	if stop == nil {
+		return
+	}
+	close(stop)
+	<-done
+}
+
+// setLocked implements Set/SetWithExtra/SetWithTTL. Must be called with
+// bm.mu held for writing.
+func (bm *BiMap[K, V, E]) setLocked(key K, value V, extra E, ttl time.Duration) {
+	bm.sweepExpiredLocked()
+
+	if oldEntry, exists := bm.keyToEntry[key]; exists && oldEntry.Value != value {
+		bm.removeLocked(key, Replaced)
+	}
+	if oldKey, exists := bm.valueToKey[value]; exists && oldKey != key {
+		bm.removeLocked(oldKey, Replaced)
+	}
+
+	if _, exists := bm.keyToEntry[key]; !exists {
+		bm.index.Insert(key)
+		bm.insertElems[key] = bm.insertOrder.PushBack(key)
+	}
+
+	var expireAt time.Time
+	if ttl > 0 {
+		expireAt = time.Now().Add(ttl)
+		bm.expiries.Set(key, expireAt)
+	} else {
+		bm.expiries.Remove(key)
+	}
+
+	bm.keyToEntry[key] = entry[V, E]{Value: value, Extra: extra, expireAt: expireAt}
+	bm.valueToKey[value] = key
+
+	bm.enforceCapacityLocked()
+}
+
+// removeLocked deletes key's entry, if present, updating every bookkeeping
+// structure and firing OnEvict with reason. Must be called with bm.mu held
+// for writing. Returns whether an entry was removed.
+func (bm *BiMap[K, V, E]) removeLocked(key K, reason EvictReason) bool {
+	e, exists := bm.keyToEntry[key]
+	if !exists {
+		return false
+	}
+
+	delete(bm.keyToEntry, key)
+	delete(bm.valueToKey, e.Value)
+	bm.index.Delete(key)
+	bm.expiries.Remove(key)
+	if elem, ok := bm.insertElems[key]; ok {
+		bm.insertOrder.Remove(elem)
+		delete(bm.insertElems, key)
+	}
+
+	if bm.onEvict != nil {
+		bm.onEvict(key, e.Value, e.Extra, reason)
+	}
+	return true
+}
+
+// sweepExpiredLocked removes every entry whose TTL has elapsed. Must be
+// called with bm.mu held for writing.
+func (bm *BiMap[K, V, E]) sweepExpiredLocked() {
+	now := time.Now()
+	for {
+		key, expireAt, ok := bm.expiries.Peek()
+		if !ok || expireAt.After(now) {
+			return
+		}
+		bm.removeLocked(key, Expired)
+	}
+}
+
+// enforceCapacityLocked evicts entries until the map is back within
+// MaxSize. Must be called with bm.mu held for writing.
+func (bm *BiMap[K, V, E]) enforceCapacityLocked() {
+	if bm.maxSize <= 0 {
+		return
+	}
+	for len(bm.keyToEntry) > bm.maxSize {
+		if key, _, ok := bm.expiries.Peek(); ok {
+			bm.removeLocked(key, CapacityExceeded)
+			continue
+		}
+		front := bm.insertOrder.Front()
+		if front == nil {
+			return
+		}
+		bm.removeLocked(front.Value.(K), CapacityExceeded)
+	}
+}
+
+// expiryItem is one entry in an expiryHeap.
+type expiryItem[K comparable] struct {
+	key      K
+	expireAt time.Time
+	idx      int
+}
+
+// expiryHeapData is the container/heap.Interface backing an expiryHeap,
+// ordered by soonest expireAt.
+type expiryHeapData[K comparable] []*expiryItem[K]
+
+func (h expiryHeapData[K]) Len() int { return len(h) }
+func (h expiryHeapData[K]) Less(i, j int) bool {
+	return h[i].expireAt.Before(h[j].expireAt)
+}
+func (h expiryHeapData[K]) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].idx, h[j].idx = i, j
+}
+func (h *expiryHeapData[K]) Push(x any) {
+	item := x.(*expiryItem[K])
+	item.idx = len(*h)
+	*h = append(*h, item)
+}
+func (h *expiryHeapData[K]) Pop() any {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	old[n-1] = nil
+	*h = old[:n-1]
+	return item
+}
+
+// expiryHeap is a min-heap of keys ordered by expiry time, with O(1) lookup
+// of a key's current heap position so its expiry can be updated or removed
+// without a scan.
+type expiryHeap[K comparable] struct {
+	data  expiryHeapData[K]
+	items map[K]*expiryItem[K]
+}
+
+func newExpiryHeap[K comparable]() *expiryHeap[K] {
+	return &expiryHeap[K]{items: make(map[K]*expiryItem[K])}
+}
+
+// Set inserts key's expiry, or updates it if key is already tracked.
+func (h *expiryHeap[K]) Set(key K, expireAt time.Time) {
+	if item, exists := h.items[key]; exists {
+		item.expireAt = expireAt
+		heap.Fix(&h.data, item.idx)
+		return
+	}
+	item := &expiryItem[K]{key: key, expireAt: expireAt}
+	heap.Push(&h.data, item)
+	h.items[key] = item
+}
+
+// Remove stops tracking key's expiry, if any.
+func (h *expiryHeap[K]) Remove(key K) {
+	item, exists := h.items[key]
+	if !exists {
+		return
+	}
+	heap.Remove(&h.data, item.idx)
+	delete(h.items, key)
+}
+
+// Peek returns the key with the soonest expiry without removing it.
+func (h *expiryHeap[K]) Peek() (K, time.Time, bool) {
+	if len(h.data) == 0 {
+		var zero K
+		return zero, time.Time{}, false
+	}
+	top := h.data[0]
+	return top.key, top.expireAt, true
+}
@@ -0,0 +1,230 @@
+package bimap
+
+import (
+	"io"
+	"sync"
+	"testing"
+	"time"
+)
+
+// evictRecord is one call captured by a test OnEvict callback.
+type evictRecord struct {
+	key    string
+	value  string
+	extra  int
+	reason EvictReason
+}
+
+// recordingEvictor collects OnEvict calls behind a mutex so tests can safely
+// read them back even when a background janitor goroutine is the caller.
+type recordingEvictor struct {
+	mu      sync.Mutex
+	records []evictRecord
+}
+
+func (r *recordingEvictor) onEvict(key, value string, extra int, reason EvictReason) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.records = append(r.records, evictRecord{key, value, extra, reason})
+}
+
+func (r *recordingEvictor) snapshot() []evictRecord {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]evictRecord(nil), r.records...)
+}
+
+func newRecordingEvictor() (*recordingEvictor, func() []evictRecord) {
+	r := &recordingEvictor{}
+	return r, r.snapshot
+}
+
+func TestBiMapSetWithTTLExpiresOnNextMutation(t *testing.T) {
+	evictor, records := newRecordingEvictor()
+	bm := NewBiMapWithOptions(Options[string, string, int]{OnEvict: evictor.onEvict})
+
+	bm.SetWithTTL("k1", "v1", 1, time.Millisecond)
+	if v, ok := bm.Get("k1"); !ok || v != "v1" {
+		t.Fatalf("Get(k1) immediately after Set = (%v, %v), want (v1, true)", v, ok)
+	}
+
+	time.Sleep(5 * time.Millisecond)
+
+	// Get doesn't sweep, so the stale entry is still visible here...
+	if _, ok := bm.Get("k1"); !ok {
+		t.Fatalf("Get(k1) before any mutation = not found, want a lazily-stale hit")
+	}
+
+	// ...but the next mutating call sweeps it out and fires OnEvict.
+	bm.Set("k2", "v2")
+
+	if _, ok := bm.Get("k1"); ok {
+		t.Fatalf("Get(k1) after sweep = found, want expired")
+	}
+	if len(records()) != 1 || records()[0].key != "k1" || records()[0].reason != Expired {
+		t.Fatalf("records = %+v, want one Expired eviction of k1", records())
+	}
+}
+
+func TestBiMapSetReplaceFiresOnEvictReplaced(t *testing.T) {
+	evictor, records := newRecordingEvictor()
+	bm := NewBiMapWithOptions(Options[string, string, int]{OnEvict: evictor.onEvict})
+
+	bm.SetWithExtra("k1", "v1", 1)
+	bm.SetWithExtra("k1", "v2", 2) // same key, new value: displaces v1
+
+	if len(records()) != 1 || records()[0].reason != Replaced || records()[0].value != "v1" {
+		t.Fatalf("records = %+v, want one Replaced eviction of v1", records())
+	}
+
+	bm.SetWithExtra("k2", "v2", 3) // same value, new key: displaces k1
+
+	if len(records()) != 2 || records()[1].reason != Replaced || records()[1].key != "k1" {
+		t.Fatalf("records = %+v, want a second Replaced eviction of k1", records())
+	}
+	if v, ok := bm.Get("k2"); !ok || v != "v2" {
+		t.Fatalf("Get(k2) = (%v, %v), want (v2, true)", v, ok)
+	}
+}
+
+func TestBiMapDeleteFiresOnEvictDeleted(t *testing.T) {
+	evictor, records := newRecordingEvictor()
+	bm := NewBiMapWithOptions(Options[string, string, int]{OnEvict: evictor.onEvict})
+
+	bm.SetWithExtra("k1", "v1", 1)
+	bm.Delete("k1")
+
+	if len(records()) != 1 || records()[0].reason != Deleted {
+		t.Fatalf("records = %+v, want one Deleted eviction", records())
+	}
+}
+
+func TestBiMapMaxSizeEvictsOldestWhenNoTTL(t *testing.T) {
+	evictor, records := newRecordingEvictor()
+	bm := NewBiMapWithOptions(Options[string, string, int]{MaxSize: 2, OnEvict: evictor.onEvict})
+
+	bm.SetWithExtra("k1", "v1", 1)
+	bm.SetWithExtra("k2", "v2", 2)
+	bm.SetWithExtra("k3", "v3", 3)
+
+	if bm.Len() != 2 {
+		t.Fatalf("Len() = %d, want 2", bm.Len())
+	}
+	if _, ok := bm.Get("k1"); ok {
+		t.Fatalf("Get(k1) = found, want evicted as oldest insertion")
+	}
+	if len(records()) != 1 || records()[0].key != "k1" || records()[0].reason != CapacityExceeded {
+		t.Fatalf("records = %+v, want one CapacityExceeded eviction of k1", records())
+	}
+}
+
+func TestBiMapMaxSizeEvictsSoonestExpiryFirst(t *testing.T) {
+	bm := NewBiMapWithOptions(Options[string, string, int]{MaxSize: 2})
+
+	bm.SetWithTTL("k1", "v1", 1, time.Hour)
+	bm.SetWithTTL("k2", "v2", 2, time.Minute) // expires sooner than k1
+	bm.SetWithTTL("k3", "v3", 3, time.Hour)
+
+	if _, ok := bm.Get("k2"); ok {
+		t.Fatalf("Get(k2) = found, want evicted as soonest-to-expire")
+	}
+	if _, ok := bm.Get("k1"); !ok {
+		t.Fatalf("Get(k1) = not found, want kept")
+	}
+	if _, ok := bm.Get("k3"); !ok {
+		t.Fatalf("Get(k3) = not found, want kept")
+	}
+}
+
+func TestBiMapStartJanitorSweepsInBackground(t *testing.T) {
+	evictor, records := newRecordingEvictor()
+	bm := NewBiMapWithOptions(Options[string, string, int]{OnEvict: evictor.onEvict})
+	defer bm.Close()
+
+	bm.SetWithTTL("k1", "v1", 1, time.Millisecond)
+	bm.StartJanitor(2 * time.Millisecond)
+
+	deadline := time.Now().Add(time.Second)
+	for len(records()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(2 * time.Millisecond)
+	}
+
+	if len(records()) != 1 || records()[0].reason != Expired {
+		t.Fatalf("records = %+v, want one background Expired eviction", records())
+	}
+}
+
+func TestBiMapCloseStopsJanitorAndIsIdempotentWithoutOne(t *testing.T) {
+	bm := NewBiMap[string, string, int]()
+	bm.Close() // no janitor running: must not panic or block
+
+	bm.StartJanitor(time.Hour)
+	bm.Close()
+	bm.Close() // closing twice must also not panic or block
+}
+
+func TestBiMapRestoreEnforcesMaxSize(t *testing.T) {
+	src := NewBiMap[string, string, int]()
+	for i := 0; i < 10; i++ {
+		src.SetWithExtra(string(rune('a'+i)), string(rune('A'+i)), i)
+	}
+
+	evictor, records := newRecordingEvictor()
+	dst := NewBiMapWithOptions(Options[string, string, int]{MaxSize: 3, OnEvict: evictor.onEvict})
+
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(src.Snapshot(pw))
+	}()
+	if err := dst.Restore(pr); err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+
+	if dst.Len() != 3 {
+		t.Fatalf("Len() = %d after restoring 10 entries into a MaxSize=3 BiMap, want 3", dst.Len())
+	}
+	if len(records()) != 7 {
+		t.Fatalf("got %d CapacityExceeded evictions, want 7", len(records()))
+	}
+	for _, r := range records() {
+		if r.reason != CapacityExceeded {
+			t.Fatalf("eviction reason = %v, want CapacityExceeded", r.reason)
+		}
+	}
+}
+
+func TestExpiryHeapSetRemovePeekOrdersBySoonest(t *testing.T) {
+	h := newExpiryHeap[string]()
+	if _, _, ok := h.Peek(); ok {
+		t.Fatalf("Peek() on empty heap returned ok=true")
+	}
+
+	now := time.Now()
+	h.Set("a", now.Add(3*time.Second))
+	h.Set("b", now.Add(1*time.Second))
+	h.Set("c", now.Add(2*time.Second))
+
+	if k, _, ok := h.Peek(); !ok || k != "b" {
+		t.Fatalf("Peek() = (%v, _, %v), want (b, true)", k, ok)
+	}
+
+	// Updating an existing key's expiry re-sorts it.
+	h.Set("b", now.Add(10*time.Second))
+	if k, _, ok := h.Peek(); !ok || k != "c" {
+		t.Fatalf("Peek() after re-setting b = (%v, _, %v), want (c, true)", k, ok)
+	}
+
+	h.Remove("c")
+	if k, _, ok := h.Peek(); !ok || k != "a" {
+		t.Fatalf("Peek() after removing c = (%v, _, %v), want (a, true)", k, ok)
+	}
+
+	h.Remove("a")
+	h.Remove("b")
+	if _, _, ok := h.Peek(); ok {
+		t.Fatalf("Peek() after removing everything returned ok=true")
+	}
+
+	// Removing a key that was never tracked is a no-op, not a panic.
+	h.Remove("never-existed")
+}